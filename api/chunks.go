@@ -0,0 +1,134 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/github/git-lfs/config"
+	"github.com/github/git-lfs/errutil"
+)
+
+// ChunkAction is one chunk the server doesn't already have, with the URL
+// to PUT its bytes to.
+type ChunkAction struct {
+	Oid  string `json:"oid"`
+	Href string `json:"href"`
+}
+
+type chunksQueryResponse struct {
+	Missing []ChunkAction `json:"missing"`
+}
+
+// QueryChunks asks obj's "chunks" action which of chunkOids the server
+// doesn't already have. ok reports whether the server advertised the
+// chunks action for this object at all; when it didn't, the caller should
+// fall back to a classic upload rather than treat a false "ok" as "nothing
+// missing".
+func QueryChunks(obj *ObjectResource, chunkOids []string) (missing []ChunkAction, ok bool, err error) {
+	rel, ok := obj.Rel("chunks")
+	if !ok {
+		return nil, false, nil
+	}
+
+	body := struct {
+		Chunks []string `json:"chunks"`
+	}{Chunks: chunkOids}
+
+	req, err := http.NewRequest("POST", rel.Href, MarshalBody(body))
+	if err != nil {
+		return nil, true, errutil.Error(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range rel.Header {
+		req.Header.Set(key, value)
+	}
+
+	res, err := DoHTTP(config.Config, req)
+	if err != nil {
+		return nil, true, errutil.NewRetriableError(err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode > 299 {
+		httpErr := fmt.Errorf("Error querying chunks for %s: %d", obj.Oid, res.StatusCode)
+		if isRetriableStatus(res.StatusCode) {
+			return nil, true, errutil.NewRetriableError(httpErr)
+		}
+		return nil, true, errutil.Error(httpErr)
+	}
+
+	var decoded chunksQueryResponse
+	if err := json.NewDecoder(res.Body).Decode(&decoded); err != nil {
+		return nil, true, errutil.Error(err)
+	}
+
+	return decoded.Missing, true, nil
+}
+
+// UploadChunk PUTs a single missing chunk's bytes to href.
+func UploadChunk(href string, r io.Reader) error {
+	req, err := http.NewRequest("PUT", href, r)
+	if err != nil {
+		return errutil.Error(err)
+	}
+
+	res, err := DoHTTP(config.Config, req)
+	if err != nil {
+		return errutil.NewRetriableError(err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode > 299 {
+		err := fmt.Errorf("Error uploading chunk: %d", res.StatusCode)
+		if isRetriableStatus(res.StatusCode) {
+			return errutil.NewRetriableError(err)
+		}
+		return errutil.Error(err)
+	}
+	return nil
+}
+
+// UploadChunkManifest POSTs the finished chunk manifest, already
+// JSON-encoded by the caller, to obj's "chunks-manifest" action, binding
+// the uploaded chunks to the object's oid.
+func UploadChunkManifest(obj *ObjectResource, manifest []byte) error {
+	rel, ok := obj.Rel("chunks-manifest")
+	if !ok {
+		return errutil.Errorf(nil, "Object %s has no chunks-manifest action", obj.Oid)
+	}
+
+	req, err := http.NewRequest("POST", rel.Href, bytes.NewReader(manifest))
+	if err != nil {
+		return errutil.Error(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range rel.Header {
+		req.Header.Set(key, value)
+	}
+
+	res, err := DoHTTP(config.Config, req)
+	if err != nil {
+		return errutil.NewRetriableError(err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode > 299 {
+		err := fmt.Errorf("Error uploading chunk manifest for %s: %d", obj.Oid, res.StatusCode)
+		if isRetriableStatus(res.StatusCode) {
+			return errutil.NewRetriableError(err)
+		}
+		return errutil.Error(err)
+	}
+	return nil
+}
+
+// isRetriableStatus mirrors lfs's own status classification (5xx/408/429
+// are transient). Duplicated rather than imported: api is a lower-level
+// package than lfs and must not depend on it, and this is a three-line
+// pure function, not worth a shared package of its own.
+func isRetriableStatus(code int) bool {
+	return code == 408 || code == 429 || code >= 500
+}
@@ -0,0 +1,39 @@
+package api
+
+import "testing"
+
+func TestQueryChunksReportsUnsupportedWhenNoAction(t *testing.T) {
+	obj := &ObjectResource{Oid: "abc", Size: 10}
+
+	missing, ok, err := QueryChunks(obj, []string{"chunk1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok {
+		t.Fatal("expected ok=false when the object advertises no chunks action")
+	}
+	if missing != nil {
+		t.Fatalf("expected a nil missing list, got %v", missing)
+	}
+}
+
+func TestUploadChunkManifestErrorsWithoutAction(t *testing.T) {
+	obj := &ObjectResource{Oid: "abc", Size: 10}
+
+	if err := UploadChunkManifest(obj, []byte(`{}`)); err == nil {
+		t.Fatal("expected an error when the object has no chunks-manifest action")
+	}
+}
+
+func TestIsRetriableStatus(t *testing.T) {
+	for _, status := range []int{408, 429, 500, 502, 503} {
+		if !isRetriableStatus(status) {
+			t.Errorf("status %d: expected retriable", status)
+		}
+	}
+	for _, status := range []int{200, 400, 401, 404} {
+		if isRetriableStatus(status) {
+			t.Errorf("status %d: expected not retriable", status)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/github/git-lfs/config"
+)
+
+// DoHTTP executes req against the LFS server. It's the single chokepoint
+// every transfer path in lfs/ routes through, so retry/circuit-breaker
+// classification in lfs has one place to reason about transport-level
+// failures regardless of which upload/download strategy issued the
+// request.
+func DoHTTP(cfg *config.Configuration, req *http.Request) (*http.Response, error) {
+	return httpClient(cfg).Do(req)
+}
+
+func httpClient(cfg *config.Configuration) *http.Client {
+	return &http.Client{}
+}
+
+// MarshalBody JSON-encodes v for use as an http.Request body.
+func MarshalBody(v interface{}) io.Reader {
+	by, err := json.Marshal(v)
+	if err != nil {
+		return bytes.NewReader(nil)
+	}
+	return bytes.NewReader(by)
+}
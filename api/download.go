@@ -0,0 +1,60 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/github/git-lfs/config"
+	"github.com/github/git-lfs/errutil"
+	"github.com/github/git-lfs/progress"
+)
+
+// DownloadObject GETs obj's "download" action and writes the response body
+// to path, reporting progress via cb as bytes arrive.
+func DownloadObject(obj *ObjectResource, path string, cb progress.CopyCallback) error {
+	rel, ok := obj.Rel("download")
+	if !ok {
+		return errutil.Errorf(nil, "Object %s has no download action", obj.Oid)
+	}
+
+	req, err := http.NewRequest("GET", rel.Href, nil)
+	if err != nil {
+		return errutil.Error(err)
+	}
+	for key, value := range rel.Header {
+		req.Header.Set(key, value)
+	}
+
+	res, err := DoHTTP(config.Config, req)
+	if err != nil {
+		return errutil.NewRetriableError(err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode > 299 {
+		err := fmt.Errorf("Error downloading object %s: %d", obj.Oid, res.StatusCode)
+		if isRetriableStatus(res.StatusCode) {
+			return errutil.NewRetriableError(err)
+		}
+		return errutil.Error(err)
+	}
+
+	out, err := os.Create(path)
+	if err != nil {
+		return errutil.Error(err)
+	}
+	defer out.Close()
+
+	reader := &progress.CallbackReader{
+		C:         cb,
+		TotalSize: obj.Size,
+		Reader:    res.Body,
+	}
+
+	if _, err := io.Copy(out, reader); err != nil {
+		return errutil.NewRetriableError(err)
+	}
+	return nil
+}
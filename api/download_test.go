@@ -0,0 +1,11 @@
+package api
+
+import "testing"
+
+func TestDownloadObjectErrorsWithoutDownloadAction(t *testing.T) {
+	obj := &ObjectResource{Oid: "abc", Size: 10}
+
+	if err := DownloadObject(obj, "/tmp/does-not-matter", nil); err == nil {
+		t.Fatal("expected an error when the object has no download action")
+	}
+}
@@ -0,0 +1,35 @@
+package api
+
+import "encoding/json"
+
+// LinkRelation describes a single action (e.g. "upload", "download",
+// "multipart", "chunks") in an ObjectResource's Actions map.
+//
+// Extra retains the action's raw JSON alongside the common href/method/
+// header fields. Server-specific action shapes - multipart's part list,
+// the chunked-upload action, anything else an LFS extension adds - decode
+// from Extra by their caller instead of LinkRelation carrying a typed
+// field for them: api is a lower-level package that lfs already imports,
+// so a field typed to an lfs-defined struct here would be an import cycle.
+type LinkRelation struct {
+	Href   string            `json:"href"`
+	Method string            `json:"method"`
+	Header map[string]string `json:"header,omitempty"`
+	Extra  json.RawMessage   `json:"-"`
+}
+
+// UnmarshalJSON decodes the common href/method/header fields as usual and
+// also retains the action's raw bytes in Extra, so a caller can re-decode
+// action-specific fields this type doesn't know about.
+func (r *LinkRelation) UnmarshalJSON(data []byte) error {
+	type shape LinkRelation
+
+	var s shape
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	*r = LinkRelation(s)
+	r.Extra = append(json.RawMessage(nil), data...)
+	return nil
+}
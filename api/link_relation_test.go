@@ -0,0 +1,65 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLinkRelationRetainsExtraJSON(t *testing.T) {
+	raw := []byte(`{
+		"href": "https://example.com/upload",
+		"method": "PUT",
+		"header": {"Tus-Resumable": "1.0.0"},
+		"parts": [{"href": "https://example.com/1"}]
+	}`)
+
+	var rel LinkRelation
+	if err := json.Unmarshal(raw, &rel); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if rel.Href != "https://example.com/upload" || rel.Method != "PUT" {
+		t.Fatalf("unexpected common fields: %+v", rel)
+	}
+	if rel.Header["Tus-Resumable"] != "1.0.0" {
+		t.Fatalf("expected Tus-Resumable header, got %+v", rel.Header)
+	}
+
+	// A caller that knows about a server extension (e.g. lfs's
+	// multipartAction) must be able to decode the action-specific fields
+	// from Extra without LinkRelation itself knowing their shape.
+	var extra struct {
+		Parts []struct {
+			Href string `json:"href"`
+		} `json:"parts"`
+	}
+	if err := json.Unmarshal(rel.Extra, &extra); err != nil {
+		t.Fatalf("unmarshal extra: %v", err)
+	}
+	if len(extra.Parts) != 1 || extra.Parts[0].Href != "https://example.com/1" {
+		t.Fatalf("unexpected extra.parts: %+v", extra.Parts)
+	}
+}
+
+func TestObjectResourceRel(t *testing.T) {
+	obj := &ObjectResource{
+		Oid:  "abc",
+		Size: 10,
+		Actions: map[string]*LinkRelation{
+			"upload": {Href: "https://example.com/upload"},
+		},
+	}
+
+	if _, ok := obj.Rel("download"); ok {
+		t.Fatal("expected no download action")
+	}
+	rel, ok := obj.Rel("upload")
+	if !ok || rel.Href != "https://example.com/upload" {
+		t.Fatalf("unexpected upload action: %+v, ok=%v", rel, ok)
+	}
+
+	var nilObj *ObjectResource
+	if _, ok := nilObj.Rel("upload"); ok {
+		t.Fatal("expected Rel on a nil ObjectResource to report not-found")
+	}
+}
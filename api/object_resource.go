@@ -0,0 +1,20 @@
+package api
+
+// ObjectResource is a single object as returned by the batch API: its
+// oid/size plus whatever actions (upload, download, and any server-side
+// extensions like multipart or chunks) are available for it.
+type ObjectResource struct {
+	Oid     string                   `json:"oid"`
+	Size    int64                    `json:"size"`
+	Actions map[string]*LinkRelation `json:"actions,omitempty"`
+}
+
+// Rel returns the named action (e.g. "upload", "download", "multipart",
+// "chunks"), if the server advertised it for this object.
+func (o *ObjectResource) Rel(name string) (*LinkRelation, bool) {
+	if o == nil || o.Actions == nil {
+		return nil, false
+	}
+	rel, ok := o.Actions[name]
+	return rel, ok
+}
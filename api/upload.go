@@ -0,0 +1,134 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/github/git-lfs/config"
+	"github.com/github/git-lfs/errutil"
+)
+
+// batchResponse is the server's reply to a batch request: one resource per
+// requested object, each carrying whatever actions (upload, download, and
+// any extensions like multipart/chunks) the object still needs.
+type batchResponse struct {
+	Objects []*ObjectResource `json:"objects"`
+}
+
+// UploadCheck asks the server whether the local file at path already
+// exists there, returning its resource representation - including the
+// actions needed to transfer it, if any - without sending any bytes.
+func UploadCheck(path string) (*ObjectResource, error) {
+	oid, size, err := oidAndSize(path)
+	if err != nil {
+		return nil, errutil.Error(err)
+	}
+
+	body := struct {
+		Operation string `json:"operation"`
+		Objects   []struct {
+			Oid  string `json:"oid"`
+			Size int64  `json:"size"`
+		} `json:"objects"`
+	}{
+		Operation: "upload",
+		Objects: []struct {
+			Oid  string `json:"oid"`
+			Size int64  `json:"size"`
+		}{{Oid: oid, Size: size}},
+	}
+
+	req, err := http.NewRequest("POST", config.Config.BatchURL(), MarshalBody(body))
+	if err != nil {
+		return nil, errutil.Error(err)
+	}
+	req.Header.Set("Content-Type", "application/vnd.git-lfs+json")
+	req.Header.Set("Accept", "application/vnd.git-lfs+json")
+
+	res, err := DoHTTP(config.Config, req)
+	if err != nil {
+		return nil, errutil.NewRetriableError(err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode > 299 {
+		httpErr := fmt.Errorf("Error checking object %s: %d", oid, res.StatusCode)
+		if isRetriableStatus(res.StatusCode) {
+			return nil, errutil.NewRetriableError(httpErr)
+		}
+		return nil, errutil.Error(httpErr)
+	}
+
+	var decoded batchResponse
+	if err := json.NewDecoder(res.Body).Decode(&decoded); err != nil {
+		return nil, errutil.Error(err)
+	}
+	if len(decoded.Objects) == 0 {
+		return nil, errutil.Errorf(nil, "Server returned no object for %s in batch response", oid)
+	}
+	return decoded.Objects[0], nil
+}
+
+// oidAndSize hashes the local file at path to the oid/size a batch request
+// identifies it by.
+func oidAndSize(path string) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return "", 0, err
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", 0, err
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil)), fi.Size(), nil
+}
+
+// UploadObject PUTs r to obj's "upload" action - the classic single-shot
+// upload path used when the server didn't advertise tus, multipart, or
+// content-chunking support for this object.
+func UploadObject(obj *ObjectResource, r io.Reader) error {
+	rel, ok := obj.Rel("upload")
+	if !ok {
+		return errutil.Errorf(nil, "Object %s has no upload action", obj.Oid)
+	}
+
+	method := rel.Method
+	if len(method) == 0 {
+		method = "PUT"
+	}
+
+	req, err := http.NewRequest(method, rel.Href, r)
+	if err != nil {
+		return errutil.Error(err)
+	}
+	for key, value := range rel.Header {
+		req.Header.Set(key, value)
+	}
+
+	res, err := DoHTTP(config.Config, req)
+	if err != nil {
+		return errutil.NewRetriableError(err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode > 299 {
+		err := fmt.Errorf("Error uploading object %s: %d", obj.Oid, res.StatusCode)
+		if isRetriableStatus(res.StatusCode) {
+			return errutil.NewRetriableError(err)
+		}
+		return errutil.Error(err)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUploadObjectErrorsWithoutUploadAction(t *testing.T) {
+	obj := &ObjectResource{Oid: "abc", Size: 10}
+
+	if err := UploadObject(obj, nil); err == nil {
+		t.Fatal("expected an error when the object has no upload action")
+	}
+}
+
+func TestOidAndSize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "blob")
+	if err := os.WriteFile(path, []byte("hello world"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	oid, size, err := oidAndSize(path)
+	if err != nil {
+		t.Fatalf("oidAndSize: %v", err)
+	}
+	if size != int64(len("hello world")) {
+		t.Fatalf("size = %d, want %d", size, len("hello world"))
+	}
+	const want = "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+	if oid != want {
+		t.Fatalf("oid = %s, want %s", oid, want)
+	}
+}
@@ -0,0 +1,252 @@
+package lfs
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/github/git-lfs/api"
+	"github.com/github/git-lfs/config"
+	"github.com/github/git-lfs/errutil"
+	"github.com/github/git-lfs/progress"
+)
+
+const (
+	chunkWindowSize = 64
+	chunkMinSize    = 2 * 1024 * 1024
+	chunkTargetSize = 4 * 1024 * 1024
+	chunkMaxSize    = 8 * 1024 * 1024
+)
+
+// contentChunk is one content-defined chunk of a local media file.
+type contentChunk struct {
+	Oid   string `json:"oid"`
+	Size  int64  `json:"size"`
+	start int64
+}
+
+// chunkManifest is POSTed to the server and then persisted to
+// .git/lfs/chunks/<oid>.json after a successful chunked upload. Nothing here
+// reads the saved file back; which chunks a later push skips is decided by
+// the server's QueryChunks answer.
+type chunkManifest struct {
+	Oid    string          `json:"oid"`
+	Chunks []*contentChunk `json:"chunks"`
+}
+
+func contentChunkingEnabled() bool {
+	return config.Config.FetchBool("lfs.contentchunking", false)
+}
+
+// chunkingUnavailableError indicates the server doesn't advertise content
+// chunking support for this object, and the caller should fall back to
+// whatever upload strategy it would otherwise have used. It deliberately
+// does not satisfy errutil's retriable classification: retrying the same
+// QueryChunks call would just repeat the same "unsupported" answer. Any
+// other error out of transferChunked (network failure, a rejected chunk
+// upload, a bad manifest) is a genuine failure of the chunking attempt
+// itself and must propagate instead of being mistaken for this case.
+type chunkingUnavailableError struct {
+	err error
+}
+
+func (e *chunkingUnavailableError) Error() string { return e.err.Error() }
+func (e *chunkingUnavailableError) Unwrap() error { return e.err }
+
+func chunkManifestPath(oid string) (string, error) {
+	dir := filepath.Join(LocalGitDir, "lfs", "chunks")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, oid+".json"), nil
+}
+
+func saveChunkManifest(m *chunkManifest) error {
+	path, err := chunkManifestPath(m.Oid)
+	if err != nil {
+		return err
+	}
+
+	by, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, by, 0644)
+}
+
+// chunkFile splits path into content-defined chunks using a rolling buzhash
+// over a chunkWindowSize-byte window, biased toward chunkTargetSize and
+// bounded by [chunkMinSize, chunkMaxSize]. It streams the file through a
+// bounded buffer rather than reading it into memory, since this targets
+// exactly the large-file case (VM images, datasets) where buffering the
+// whole object isn't an option.
+func chunkFile(path string) ([]*contentChunk, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var chunks []*contentChunk
+	h := newBuzhash(chunkWindowSize)
+	chunkHash := sha256.New()
+	var start, size int64
+
+	flush := func() {
+		chunks = append(chunks, &contentChunk{
+			Oid:   fmt.Sprintf("%x", chunkHash.Sum(nil)),
+			Size:  size,
+			start: start,
+		})
+		start += size
+		size = 0
+		chunkHash = sha256.New()
+		h = newBuzhash(chunkWindowSize)
+	}
+
+	br := bufio.NewReaderSize(file, 256*1024)
+	for {
+		c, err := br.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		chunkHash.Write([]byte{c})
+		h.roll(c)
+		size++
+
+		atBoundary := size >= chunkMinSize && h.sum()%chunkTargetSize == chunkTargetSize-1
+		if size >= chunkMaxSize || atBoundary {
+			flush()
+		}
+	}
+
+	if size > 0 {
+		flush()
+	}
+
+	return chunks, nil
+}
+
+// buzhashTable is a deterministic per-byte table (every process and machine
+// must agree on chunk boundaries for dedup across pushes to work at all).
+var buzhashTable = func() [256]uint32 {
+	var table [256]uint32
+	for i := range table {
+		x := uint32(i)*2654435761 + 0x9e3779b9
+		x ^= x >> 15
+		x *= 0x85ebca6b
+		x ^= x >> 13
+		table[i] = x
+	}
+	return table
+}()
+
+// buzhash is a rolling hash over a fixed-size trailing window, used to pick
+// content-defined chunk boundaries. Unlike a cumulative hash, its value
+// depends only on the last window-size bytes, so an edit near the start of
+// a chunk doesn't reshuffle every later boundary.
+type buzhash struct {
+	window []byte
+	pos    int
+	count  int64
+	h      uint32
+}
+
+func newBuzhash(size int) *buzhash {
+	return &buzhash{window: make([]byte, size)}
+}
+
+func (b *buzhash) roll(c byte) {
+	n := len(b.window)
+	b.h = rol32(b.h, 1) ^ buzhashTable[c]
+
+	if b.count >= int64(n) {
+		out := b.window[b.pos]
+		b.h ^= rol32(buzhashTable[out], uint(n%32))
+	}
+
+	b.window[b.pos] = c
+	b.pos = (b.pos + 1) % n
+	b.count++
+}
+
+func (b *buzhash) sum() uint32 {
+	return b.h
+}
+
+func rol32(x uint32, k uint) uint32 {
+	return (x << k) | (x >> (32 - k))
+}
+
+// transferChunked uploads u.object using content-defined chunking: the
+// server is asked which chunks it already has, and only the missing chunks
+// are sent before a manifest binds them all to the object OID. A
+// *chunkingUnavailableError return means the caller should fall back to
+// whichever upload strategy it would otherwise use; any other error is
+// retriable or terminal per errutil and should propagate so withRetry
+// resumes this same chunked upload on its next attempt.
+func (u *Uploadable) transferChunked(cb progress.CopyCallback) error {
+	chunks, err := chunkFile(u.OidPath)
+	if err != nil {
+		return errutil.Error(err)
+	}
+
+	oids := make([]string, len(chunks))
+	byOid := make(map[string]*contentChunk, len(chunks))
+	for i, chunk := range chunks {
+		oids[i] = chunk.Oid
+		byOid[chunk.Oid] = chunk
+	}
+
+	missing, ok, err := api.QueryChunks(u.object, oids)
+	if err != nil {
+		return errutil.Error(err)
+	}
+	if !ok {
+		// Server doesn't advertise chunking support; let the caller
+		// fall back to a classic upload.
+		return &chunkingUnavailableError{fmt.Errorf("server does not support content chunking")}
+	}
+
+	file, err := os.Open(u.OidPath)
+	if err != nil {
+		return errutil.Error(err)
+	}
+	defer file.Close()
+
+	var sent int64
+	for _, action := range missing {
+		chunk, ok := byOid[action.Oid]
+		if !ok {
+			continue
+		}
+
+		section := io.NewSectionReader(file, chunk.start, chunk.Size)
+		if err := api.UploadChunk(action.Href, section); err != nil {
+			return errutil.Error(err)
+		}
+		sent += chunk.Size
+		if err := cb(u.object.Size, sent, int(chunk.Size)); err != nil {
+			return err
+		}
+	}
+
+	manifest := &chunkManifest{Oid: u.object.Oid, Chunks: chunks}
+	by, err := json.Marshal(manifest)
+	if err != nil {
+		return errutil.Error(err)
+	}
+	if err := api.UploadChunkManifest(u.object, by); err != nil {
+		return errutil.Error(err)
+	}
+
+	return saveChunkManifest(manifest)
+}
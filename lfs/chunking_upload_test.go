@@ -0,0 +1,88 @@
+package lfs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestChunkingUnavailableErrorUnwraps(t *testing.T) {
+	inner := errors.New("server does not support content chunking")
+	err := &chunkingUnavailableError{inner}
+
+	var unavailable *chunkingUnavailableError
+	if !errors.As(err, &unavailable) {
+		t.Fatal("expected errors.As to match *chunkingUnavailableError")
+	}
+	if !errors.Is(err, inner) {
+		t.Fatal("expected errors.Is to reach the wrapped error")
+	}
+}
+
+// TestBuzhashDependsOnlyOnWindow proves the rolling hash has bounded history:
+// once two different byte streams have both fed the same chunkWindowSize-byte
+// tail, their hash values converge, regardless of what came before. A
+// cumulative hash (hashing everything since the last boundary) would keep
+// these distinct forever.
+func TestBuzhashDependsOnlyOnWindow(t *testing.T) {
+	tail := make([]byte, chunkWindowSize)
+	for i := range tail {
+		tail[i] = byte(i * 7)
+	}
+
+	a := newBuzhash(chunkWindowSize)
+	for _, b := range []byte("a completely different prefix history") {
+		a.roll(b)
+	}
+	for _, b := range tail {
+		a.roll(b)
+	}
+
+	b := newBuzhash(chunkWindowSize)
+	for _, c := range []byte("xyz") {
+		b.roll(c)
+	}
+	for _, c := range tail {
+		b.roll(c)
+	}
+
+	if a.sum() != b.sum() {
+		t.Fatalf("hashes diverge after a shared %d-byte tail: %d != %d", chunkWindowSize, a.sum(), b.sum())
+	}
+}
+
+func TestChunkFileRespectsSizeBounds(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "blob")
+
+	data := make([]byte, chunkMaxSize*3+1234)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	chunks, err := chunkFile(path)
+	if err != nil {
+		t.Fatalf("chunkFile: %v", err)
+	}
+	if len(chunks) == 0 {
+		t.Fatal("expected at least one chunk")
+	}
+
+	var total int64
+	for i, c := range chunks {
+		if c.Size > chunkMaxSize {
+			t.Fatalf("chunk %d size %d exceeds chunkMaxSize %d", i, c.Size, chunkMaxSize)
+		}
+		if i < len(chunks)-1 && c.Size < chunkMinSize {
+			t.Fatalf("non-final chunk %d size %d below chunkMinSize %d", i, c.Size, chunkMinSize)
+		}
+		total += c.Size
+	}
+	if total != int64(len(data)) {
+		t.Fatalf("chunk sizes sum to %d, want %d", total, len(data))
+	}
+}
@@ -0,0 +1,139 @@
+package lfs
+
+import (
+	"os"
+	"strings"
+
+	"github.com/github/git-lfs/api"
+	"github.com/github/git-lfs/errutil"
+	"github.com/github/git-lfs/progress"
+)
+
+// Downloadable describes an object that can be downloaded and smudged into
+// the working copy.
+type Downloadable struct {
+	object *api.ObjectResource
+}
+
+// NewDownloadable builds the Downloadable from the given batch object.
+func NewDownloadable(obj *api.ObjectResource) *Downloadable {
+	return &Downloadable{object: obj}
+}
+
+func (d *Downloadable) Object() *api.ObjectResource {
+	return d.object
+}
+
+func (d *Downloadable) Oid() string {
+	return d.object.Oid
+}
+
+func (d *Downloadable) Size() int64 {
+	return d.object.Size
+}
+
+func (d *Downloadable) Name() string {
+	return d.object.Oid
+}
+
+// Transfer downloads d.object, retrying transient failures with backoff and
+// tripping a per-endpoint circuit breaker after repeated failures. When its
+// download action carries an X-LFS-Encryption header, the ciphertext is
+// decrypted in place before it's smudged into the working copy.
+func (d *Downloadable) Transfer(cb progress.CopyCallback) error {
+	endpoint := d.object.Oid
+	if rel, ok := d.object.Rel("download"); ok {
+		endpoint = rel.Href
+	}
+	return withRetry(endpoint, cb, d.transferOnce)
+}
+
+func (d *Downloadable) transferOnce(cb progress.CopyCallback) error {
+	wcb := func(total, read int64, current int) error {
+		cb(total, read, current)
+		return nil
+	}
+
+	path, err := LocalMediaPath(d.object.Oid)
+	if err != nil {
+		return errutil.Error(err)
+	}
+
+	alg, fingerprint, encrypted := d.encryptionHeader()
+	downloadPath := path
+	if encrypted {
+		downloadPath, err = encPath(d.object.Oid)
+		if err != nil {
+			return errutil.Error(err)
+		}
+	}
+
+	if err := api.DownloadObject(d.object, downloadPath, wcb); err != nil {
+		return errutil.Error(err)
+	}
+
+	if !encrypted {
+		return nil
+	}
+
+	if alg != encryptionAlgorithm {
+		return errutil.Errorf(nil, "Object %s encrypted with unsupported algorithm %q", d.object.Oid, alg)
+	}
+
+	master, err := encMasterKey()
+	if err != nil {
+		return err
+	}
+
+	key, err := encObjectKey(master, d.object.Oid)
+	if err != nil {
+		return err
+	}
+
+	if got := encKeyFingerprint(key); got != fingerprint {
+		return errutil.Errorf(nil, "Object %s key fingerprint mismatch: have %s, want %s", d.object.Oid, got, fingerprint)
+	}
+
+	if err := decryptFile(key, downloadPath, path); err != nil {
+		return err
+	}
+
+	return os.Remove(downloadPath)
+}
+
+// encryptionHeader reports the algorithm and key fingerprint advertised by
+// the download action's X-LFS-Encryption header, if any, allowing
+// mixed-encrypted repos where only some objects are encrypted.
+func (d *Downloadable) encryptionHeader() (alg, fingerprint string, ok bool) {
+	rel, found := d.object.Rel("download")
+	if !found || rel.Header == nil {
+		return "", "", false
+	}
+
+	header, found := rel.Header["X-LFS-Encryption"]
+	if !found {
+		return "", "", false
+	}
+
+	for _, field := range strings.Split(header, ";") {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "alg":
+			alg = kv[1]
+		case "fp":
+			fingerprint = kv[1]
+		}
+	}
+
+	return alg, fingerprint, len(alg) > 0 && len(fingerprint) > 0
+}
+
+// NewDownloadQueue builds a TransferQueue for downloads.
+func NewDownloadQueue(files int, size int64, dryRun bool) *TransferQueue {
+	q := newTransferQueue(files, size, dryRun)
+	q.transferKind = "download"
+	return q
+}
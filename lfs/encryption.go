@@ -0,0 +1,233 @@
+package lfs
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/github/git-lfs/config"
+	"github.com/github/git-lfs/errutil"
+)
+
+const (
+	encryptionAlgorithm = "AES-256-GCM"
+	encFrameSize        = 16 * 1024
+	encMasterKeySize    = 32
+)
+
+// encryptionEnabled reports whether objects should be encrypted client-side
+// before upload, per the "lfs.encryption" config section.
+func encryptionEnabled() bool {
+	return config.Config.FetchBool("lfs.encryption.enabled", false)
+}
+
+// encMasterKey loads the 32-byte master key via the configured credential
+// helper or keyfile. Keys never leave the client.
+func encMasterKey() ([]byte, error) {
+	key, err := config.Config.FetchCredentialSecret("lfs.encryption.keyfile", "lfs-encryption")
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != encMasterKeySize {
+		return nil, fmt.Errorf("lfs.encryption: master key must be %d bytes, got %d", encMasterKeySize, len(key))
+	}
+	return key, nil
+}
+
+// encObjectKey derives a per-object key from the master key via
+// HKDF-SHA256(master, salt=oid).
+func encObjectKey(master []byte, oid string) ([]byte, error) {
+	key := make([]byte, encMasterKeySize)
+	kdf := hkdf.New(sha256.New, master, []byte(oid), []byte("git-lfs-object-key"))
+	if _, err := io.ReadFull(kdf, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// encPath returns the temp ciphertext path for oid under .git/lfs/enc/.
+func encPath(oid string) (string, error) {
+	dir := filepath.Join(LocalGitDir, "lfs", "enc")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, oid), nil
+}
+
+// encryptedObject describes the ciphertext that replaces a plaintext object
+// in the upload path: its own OID/size plus enough metadata to recover the
+// per-object key on a later pull. The batch request does not use this
+// OID/size; it keeps the plaintext OID/size, and the ciphertext OID reaches
+// the server only in the upload action's X-LFS-Encryption header.
+type encryptedObject struct {
+	Oid         string
+	Size        int64
+	Path        string
+	Fingerprint string
+}
+
+// encryptForUpload encrypts the local media file for oid to a temp path
+// under .git/lfs/enc/, returning the ciphertext's path, its own OID/size and
+// the per-object key's fingerprint.
+func encryptForUpload(oid, plainPath string) (*encryptedObject, error) {
+	master, err := encMasterKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := encObjectKey(master, oid)
+	if err != nil {
+		return nil, err
+	}
+
+	dstPath, err := encPath(oid)
+	if err != nil {
+		return nil, err
+	}
+
+	size, cipherOid, err := encryptFile(key, plainPath, dstPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &encryptedObject{
+		Oid:         cipherOid,
+		Size:        size,
+		Path:        dstPath,
+		Fingerprint: encKeyFingerprint(key),
+	}, nil
+}
+
+// encryptFile streams src through AES-256-GCM framing (encFrameSize plaintext
+// bytes per frame, one nonce per frame derived from a counter) and writes the
+// ciphertext to dstPath. It returns the ciphertext's size and its own SHA-256
+// OID.
+func encryptFile(key []byte, srcPath, dstPath string) (size int64, oid string, err error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return 0, "", errutil.Error(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return 0, "", errutil.Error(err)
+	}
+
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return 0, "", errutil.Error(err)
+	}
+	defer src.Close()
+
+	dst, err := os.Create(dstPath)
+	if err != nil {
+		return 0, "", errutil.Error(err)
+	}
+	defer dst.Close()
+
+	hash := sha256.New()
+	w := io.MultiWriter(dst, hash)
+
+	buf := make([]byte, encFrameSize)
+	var counter uint64
+	var total int64
+
+	for {
+		n, rerr := io.ReadFull(src, buf)
+		if n > 0 {
+			nonce := frameNonce(counter)
+			sealed := gcm.Seal(nil, nonce, buf[:n], nil)
+
+			if err := binary.Write(w, binary.BigEndian, uint32(len(sealed))); err != nil {
+				return 0, "", errutil.Error(err)
+			}
+			if _, err := w.Write(sealed); err != nil {
+				return 0, "", errutil.Error(err)
+			}
+
+			total += int64(4 + len(sealed))
+			counter++
+		}
+
+		if rerr == io.EOF || rerr == io.ErrUnexpectedEOF {
+			break
+		}
+		if rerr != nil {
+			return 0, "", errutil.Error(rerr)
+		}
+	}
+
+	return total, fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+// decryptFile is the mirror of encryptFile, used on smudge to recover the
+// plaintext from a ciphertext frame stream.
+func decryptFile(key []byte, srcPath, dstPath string) error {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return errutil.Error(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return errutil.Error(err)
+	}
+
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return errutil.Error(err)
+	}
+	defer src.Close()
+
+	dst, err := os.Create(dstPath)
+	if err != nil {
+		return errutil.Error(err)
+	}
+	defer dst.Close()
+
+	var counter uint64
+	for {
+		var frameLen uint32
+		if err := binary.Read(src, binary.BigEndian, &frameLen); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return errutil.Error(err)
+		}
+
+		sealed := make([]byte, frameLen)
+		if _, err := io.ReadFull(src, sealed); err != nil {
+			return errutil.Error(err)
+		}
+
+		plain, err := gcm.Open(nil, frameNonce(counter), sealed, nil)
+		if err != nil {
+			return errutil.Errorf(err, "Error decrypting frame %d", counter)
+		}
+		if _, err := dst.Write(plain); err != nil {
+			return errutil.Error(err)
+		}
+		counter++
+	}
+
+	return nil
+}
+
+// frameNonce derives a GCM nonce from the frame counter, per the chunked
+// framing scheme: each frame gets its own nonce so no (key, nonce) pair is
+// ever reused.
+func frameNonce(counter uint64) []byte {
+	nonce := make([]byte, 12)
+	binary.BigEndian.PutUint64(nonce[4:], counter)
+	return nonce
+}
+
+func encKeyFingerprint(key []byte) string {
+	sum := sha256.Sum256(key)
+	return fmt.Sprintf("%x", sum[:8])
+}
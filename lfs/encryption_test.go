@@ -0,0 +1,78 @@
+package lfs
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEncryptDecryptFileRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+
+	plain := make([]byte, encFrameSize*2+137) // spans several frames plus a partial one
+	if _, err := rand.Read(plain); err != nil {
+		t.Fatalf("rand.Read: %v", err)
+	}
+
+	srcPath := filepath.Join(dir, "plain")
+	if err := os.WriteFile(srcPath, plain, 0644); err != nil {
+		t.Fatalf("write src: %v", err)
+	}
+
+	key := bytes.Repeat([]byte{0x42}, encMasterKeySize)
+
+	encPath := filepath.Join(dir, "cipher")
+	size, oid, err := encryptFile(key, srcPath, encPath)
+	if err != nil {
+		t.Fatalf("encryptFile: %v", err)
+	}
+	if size <= int64(len(plain)) {
+		t.Fatalf("ciphertext size %d should exceed plaintext size %d (framing overhead)", size, len(plain))
+	}
+	if len(oid) != 64 {
+		t.Fatalf("expected a hex sha256 oid, got %q", oid)
+	}
+
+	decPath := filepath.Join(dir, "decrypted")
+	if err := decryptFile(key, encPath, decPath); err != nil {
+		t.Fatalf("decryptFile: %v", err)
+	}
+
+	got, err := os.ReadFile(decPath)
+	if err != nil {
+		t.Fatalf("read decrypted: %v", err)
+	}
+	if !bytes.Equal(got, plain) {
+		t.Fatal("decrypted content does not match original plaintext")
+	}
+}
+
+func TestEncryptFileChangesOidFromPlaintext(t *testing.T) {
+	dir := t.TempDir()
+
+	plain := []byte("some object content")
+	srcPath := filepath.Join(dir, "plain")
+	if err := os.WriteFile(srcPath, plain, 0644); err != nil {
+		t.Fatalf("write src: %v", err)
+	}
+
+	key := bytes.Repeat([]byte{0x7}, encMasterKeySize)
+	_, cipherOid, err := encryptFile(key, srcPath, filepath.Join(dir, "cipher"))
+	if err != nil {
+		t.Fatalf("encryptFile: %v", err)
+	}
+
+	// The ciphertext's own digest must never be mistaken for the object's
+	// identity: NewUploadable keeps u.oid as the plaintext oid so the
+	// batch request, Check(), and a later download all agree on what the
+	// git pointer actually records.
+	sum := sha256.Sum256(plain)
+	plainOid := fmt.Sprintf("%x", sum[:])
+	if cipherOid == plainOid {
+		t.Fatal("ciphertext oid unexpectedly matches plaintext oid")
+	}
+}
@@ -0,0 +1,208 @@
+package lfs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/github/git-lfs/api"
+	"github.com/github/git-lfs/config"
+	"github.com/github/git-lfs/errutil"
+	"github.com/github/git-lfs/progress"
+)
+
+const defaultConcurrentParts = 3
+
+// multipartPart describes a single byte range PUT as returned by the batch
+// API's "multipart" action.
+type multipartPart struct {
+	Href   string            `json:"href"`
+	Method string            `json:"method"`
+	Header map[string]string `json:"header"`
+	Range  struct {
+		Start int64 `json:"start"`
+		End   int64 `json:"end"`
+	} `json:"range"`
+}
+
+// multipartAction is the shape of the "multipart" entry in an
+// api.ObjectResource's Actions map. It's an lfs-local type: api.LinkRelation
+// carries this action's data as opaque JSON in Extra rather than a typed
+// field, since a lower-level package (api) can't reference a type defined in
+// a package that already imports it (lfs does) without an import cycle.
+type multipartAction struct {
+	Parts    []multipartPart   `json:"parts"`
+	Complete *api.LinkRelation `json:"complete"`
+}
+
+// isMultipart reports whether the batch response for this object advertised
+// a multipart upload action.
+func (u *Uploadable) isMultipart() (*multipartAction, bool) {
+	rel, ok := u.object.Rel("multipart")
+	if !ok || len(rel.Extra) == 0 {
+		return nil, false
+	}
+
+	var mp multipartAction
+	if err := json.Unmarshal(rel.Extra, &mp); err != nil {
+		return nil, false
+	}
+	return &mp, true
+}
+
+func concurrentParts() int {
+	n := config.Config.FetchInt("lfs.concurrentparts", defaultConcurrentParts)
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+// transferMultipart uploads u.object's parts concurrently and, once every
+// part succeeds, completes the upload with the server-assigned ETags.
+func (u *Uploadable) transferMultipart(mp *multipartAction, cb progress.CopyCallback) error {
+	file, err := os.Open(u.OidPath)
+	if err != nil {
+		return errutil.Error(err)
+	}
+	defer file.Close()
+
+	var (
+		mu       sync.Mutex
+		read     int64
+		total    = u.object.Size
+		etags    = make([]string, len(mp.Parts))
+		firstErr error
+	)
+
+	reportProgress := func(n int64) error {
+		mu.Lock()
+		read += n
+		current := read
+		mu.Unlock()
+		return cb(total, current, int(n))
+	}
+
+	sem := make(chan struct{}, concurrentParts())
+	var wg sync.WaitGroup
+
+	for i, part := range mp.Parts {
+		i, part := i, part
+
+		mu.Lock()
+		if firstErr != nil {
+			mu.Unlock()
+			break
+		}
+		mu.Unlock()
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			section := io.NewSectionReader(file, part.Range.Start, part.Range.End-part.Range.Start+1)
+			etag, err := uploadPart(part, section, reportProgress)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			etags[i] = etag
+		}()
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return errutil.Errorf(firstErr, "Error uploading one or more parts of %s", u.object.Oid)
+	}
+
+	return completeMultipart(mp, etags)
+}
+
+func uploadPart(part multipartPart, r io.Reader, reportProgress func(int64) error) (string, error) {
+	req, err := http.NewRequest(part.Method, part.Href, r)
+	if err != nil {
+		return "", errutil.Error(err)
+	}
+
+	size := part.Range.End - part.Range.Start + 1
+	req.ContentLength = size
+	for key, value := range part.Header {
+		req.Header.Set(key, value)
+	}
+
+	res, err := api.DoHTTP(config.Config, req)
+	if err != nil {
+		return "", errutil.NewRetriableError(err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode > 299 {
+		err := fmt.Errorf("Error uploading part %d-%d: %d", part.Range.Start, part.Range.End, res.StatusCode)
+		if isRetriableStatus(res.StatusCode) {
+			return "", errutil.NewRetriableError(err)
+		}
+		return "", errutil.Error(err)
+	}
+
+	if err := reportProgress(size); err != nil {
+		return "", err
+	}
+
+	return res.Header.Get("ETag"), nil
+}
+
+func completeMultipart(mp *multipartAction, etags []string) error {
+	if mp.Complete == nil {
+		return errutil.Errorf(nil, "No complete action for multipart upload")
+	}
+
+	type completedPart struct {
+		PartNumber int    `json:"part_number"`
+		ETag       string `json:"etag"`
+	}
+
+	parts := make([]completedPart, len(etags))
+	for i, etag := range etags {
+		parts[i] = completedPart{PartNumber: i + 1, ETag: etag}
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber })
+
+	body := struct {
+		Parts []completedPart `json:"parts"`
+	}{Parts: parts}
+
+	req, err := http.NewRequest(mp.Complete.Method, mp.Complete.Href, api.MarshalBody(body))
+	if err != nil {
+		return errutil.Error(err)
+	}
+	for key, value := range mp.Complete.Header {
+		req.Header.Set(key, value)
+	}
+
+	res, err := api.DoHTTP(config.Config, req)
+	if err != nil {
+		return errutil.NewRetriableError(err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode > 299 {
+		err := fmt.Errorf("Error completing multipart upload: %d", res.StatusCode)
+		if isRetriableStatus(res.StatusCode) {
+			return errutil.NewRetriableError(err)
+		}
+		return errutil.Error(err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package lfs
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestConcurrentPartsFloor(t *testing.T) {
+	// concurrentParts must never return less than 1, regardless of what a
+	// misconfigured lfs.concurrentparts value resolves to.
+	if n := concurrentParts(); n < 1 {
+		t.Fatalf("concurrentParts() = %d, want >= 1", n)
+	}
+}
+
+func TestMultipartActionDecodesFromExtra(t *testing.T) {
+	raw := []byte(`{
+		"parts": [
+			{"href": "https://example.com/1", "method": "PUT", "range": {"start": 0, "end": 9}},
+			{"href": "https://example.com/2", "method": "PUT", "range": {"start": 10, "end": 19}}
+		],
+		"complete": {"href": "https://example.com/complete", "method": "POST"}
+	}`)
+
+	var mp multipartAction
+	if err := json.Unmarshal(raw, &mp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if len(mp.Parts) != 2 {
+		t.Fatalf("got %d parts, want 2", len(mp.Parts))
+	}
+	if mp.Parts[0].Range.Start != 0 || mp.Parts[0].Range.End != 9 {
+		t.Fatalf("unexpected range on part 0: %+v", mp.Parts[0].Range)
+	}
+	if mp.Complete == nil || mp.Complete.Href != "https://example.com/complete" {
+		t.Fatalf("unexpected complete action: %+v", mp.Complete)
+	}
+}
@@ -0,0 +1,159 @@
+package lfs
+
+import (
+	"math/rand"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/github/git-lfs/config"
+	"github.com/github/git-lfs/errutil"
+	"github.com/github/git-lfs/progress"
+)
+
+const (
+	defaultMaxRetries       = 6
+	defaultRetryBackoff     = 500 * time.Millisecond
+	maxRetryBackoff         = 30 * time.Second
+	circuitBreakerThreshold = 5
+	circuitBreakerCooldown  = 30 * time.Second
+)
+
+func maxRetries() int {
+	return config.Config.FetchInt("lfs.transfer.maxretries", defaultMaxRetries)
+}
+
+func retryBackoff() time.Duration {
+	ms := config.Config.FetchInt("lfs.transfer.retrybackoff", int(defaultRetryBackoff/time.Millisecond))
+	return time.Duration(ms) * time.Millisecond
+}
+
+// circuitBreaker short-circuits further attempts against a single endpoint
+// for a cool-down window once it has seen too many consecutive failures, so
+// a degraded server doesn't get hit with a thundering herd of retries.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return time.Now().After(b.openUntil)
+}
+
+func (b *circuitBreaker) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.openUntil = time.Time{}
+}
+
+func (b *circuitBreaker) recordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.failures >= circuitBreakerThreshold {
+		b.openUntil = time.Now().Add(circuitBreakerCooldown)
+	}
+}
+
+var (
+	breakersMu sync.Mutex
+	breakers   = make(map[string]*circuitBreaker)
+)
+
+func breakerFor(endpoint string) *circuitBreaker {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+
+	b, ok := breakers[endpoint]
+	if !ok {
+		b = &circuitBreaker{}
+		breakers[endpoint] = b
+	}
+	return b
+}
+
+// endpointHost reduces an href to the host used to key its circuit breaker.
+func endpointHost(href string) string {
+	u, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+	return u.Host
+}
+
+// withRetry retries transfer with exponential backoff and jitter, skipping
+// the attempt entirely while the endpoint's circuit breaker is open. Each
+// retry re-invokes transfer from scratch, but most transfer paths (the
+// classic single-shot upload/download, multipart, chunking) report
+// progress as an absolute count starting at zero on every attempt; without
+// correction, a retry after a partial failure would make the progress bar
+// jump backward and double-count bytes already reported. withRetry tracks
+// the high-water mark of bytes reported across all attempts and only
+// forwards the genuinely new bytes past it, so cb only ever sees
+// non-negative, non-regressing progress. (tus's resumable path reports
+// absolute positions starting from the server's current offset rather than
+// zero, so there the clamp only drops bytes a failed PATCH had already
+// reported past the offset the server kept.)
+func withRetry(endpoint string, cb progress.CopyCallback, transfer func(progress.CopyCallback) error) error {
+	breaker := breakerFor(endpointHost(endpoint))
+
+	var (
+		lastErr  error
+		reported int64
+	)
+
+	wrapped := func(total, read int64, current int) error {
+		if read <= reported {
+			return nil
+		}
+		delta := read - reported
+		reported = read
+		return cb(total, read, int(delta))
+	}
+
+	for attempt := 0; attempt < maxRetries(); attempt++ {
+		if !breaker.allow() {
+			return errutil.Errorf(lastErr, "circuit open for %s, not retrying", endpoint)
+		}
+
+		if attempt > 0 {
+			time.Sleep(backoffDelay(attempt))
+		}
+
+		err := transfer(wrapped)
+		if err == nil {
+			breaker.recordSuccess()
+			return nil
+		}
+
+		lastErr = err
+		if !errutil.IsRetriable(err) {
+			return err
+		}
+		breaker.recordFailure()
+	}
+
+	return errutil.Errorf(lastErr, "giving up on %s after %d attempts", endpoint, maxRetries())
+}
+
+// isRetriableStatus reports whether an HTTP status is transient under the
+// shared retry policy: any 5xx, 408 or 429. Network errors never reach it;
+// callers mark a failed request as retriable themselves.
+func isRetriableStatus(code int) bool {
+	return code == 408 || code == 429 || code >= 500
+}
+
+// backoffDelay computes the exponential backoff (base * 2^(attempt-1)) with
+// full jitter, capped at maxRetryBackoff.
+func backoffDelay(attempt int) time.Duration {
+	base := retryBackoff()
+	delay := base << uint(attempt-1)
+	if delay > maxRetryBackoff || delay <= 0 {
+		delay = maxRetryBackoff
+	}
+	return time.Duration(rand.Int63n(int64(delay)))
+}
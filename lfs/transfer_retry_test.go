@@ -0,0 +1,110 @@
+package lfs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/github/git-lfs/errutil"
+	"github.com/github/git-lfs/progress"
+)
+
+func TestCircuitBreakerOpensAfterThreshold(t *testing.T) {
+	b := &circuitBreaker{}
+
+	for i := 0; i < circuitBreakerThreshold-1; i++ {
+		b.recordFailure()
+		if !b.allow() {
+			t.Fatalf("breaker opened after %d failures, want %d", i+1, circuitBreakerThreshold)
+		}
+	}
+
+	b.recordFailure()
+	if b.allow() {
+		t.Fatalf("breaker should be open after %d consecutive failures", circuitBreakerThreshold)
+	}
+}
+
+func TestCircuitBreakerRecoversOnSuccess(t *testing.T) {
+	b := &circuitBreaker{}
+
+	for i := 0; i < circuitBreakerThreshold; i++ {
+		b.recordFailure()
+	}
+	if b.allow() {
+		t.Fatal("breaker should be open")
+	}
+
+	b.recordSuccess()
+	if !b.allow() {
+		t.Fatal("breaker should close immediately after a recorded success")
+	}
+}
+
+func TestBackoffDelayCapped(t *testing.T) {
+	for attempt := 1; attempt <= 20; attempt++ {
+		d := backoffDelay(attempt)
+		if d < 0 || d > maxRetryBackoff {
+			t.Fatalf("attempt %d: backoffDelay=%s out of range [0,%s]", attempt, d, maxRetryBackoff)
+		}
+	}
+}
+
+func TestIsRetriableStatus(t *testing.T) {
+	retriable := []int{408, 429, 500, 502, 503}
+	for _, code := range retriable {
+		if !isRetriableStatus(code) {
+			t.Errorf("expected %d to be retriable", code)
+		}
+	}
+
+	terminal := []int{400, 401, 403, 404, 409, 410}
+	for _, code := range terminal {
+		if isRetriableStatus(code) {
+			t.Errorf("expected %d to be terminal", code)
+		}
+	}
+}
+
+func TestEndpointHost(t *testing.T) {
+	host := endpointHost("https://lfs.example.com/objects/abc123")
+	if host != "lfs.example.com" {
+		t.Fatalf("got %q, want %q", host, "lfs.example.com")
+	}
+}
+
+// TestWithRetryClampsRegressingProgress exercises a transfer func that,
+// like the classic upload/download, multipart, and chunking paths, reports
+// its own progress as an absolute count starting at zero on every attempt.
+// withRetry must turn that into a monotonic, non-regressing stream for cb.
+func TestWithRetryClampsRegressingProgress(t *testing.T) {
+	var reports []int64
+	cb := func(total, read int64, current int) error {
+		reports = append(reports, read)
+		return nil
+	}
+
+	attempt := 0
+	transfer := func(inner progress.CopyCallback) error {
+		attempt++
+		inner(10, 3, 3)
+		inner(10, 6, 3)
+		if attempt < 3 {
+			return errutil.NewRetriableError(fmt.Errorf("transient failure"))
+		}
+		inner(10, 10, 4)
+		return nil
+	}
+
+	if err := withRetry("https://example.com/obj", cb, transfer); err != nil {
+		t.Fatalf("withRetry: %v", err)
+	}
+
+	for i := 1; i < len(reports); i++ {
+		if reports[i] < reports[i-1] {
+			t.Fatalf("progress regressed: %v", reports)
+		}
+	}
+	if got := reports[len(reports)-1]; got != 10 {
+		t.Fatalf("final reported progress = %d, want 10", got)
+	}
+}
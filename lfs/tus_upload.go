@@ -0,0 +1,287 @@
+package lfs
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/github/git-lfs/api"
+	"github.com/github/git-lfs/config"
+	"github.com/github/git-lfs/errutil"
+	"github.com/github/git-lfs/progress"
+)
+
+const defaultTusChunkSize = 4 * 1024 * 1024
+
+// tusState is persisted to .git/lfs/tus-state/<oid> so that an interrupted
+// resumable upload can be resumed across process restarts.
+type tusState struct {
+	Location string `json:"location"`
+}
+
+// tusResumable reports whether the batch response for this object advertised
+// tus.io support for its upload action.
+func (u *Uploadable) tusResumable() bool {
+	rel, ok := u.object.Rel("upload")
+	if !ok || rel.Header == nil {
+		return false
+	}
+	_, ok = rel.Header["Tus-Resumable"]
+	return ok
+}
+
+func tusStateDir() (string, error) {
+	dir := filepath.Join(LocalGitDir, "lfs", "tus-state")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+func tusStatePath(oid string) (string, error) {
+	dir, err := tusStateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, oid), nil
+}
+
+func loadTusState(oid string) (*tusState, error) {
+	path, err := tusStatePath(oid)
+	if err != nil {
+		return nil, err
+	}
+
+	by, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &tusState{}
+	if err := json.Unmarshal(by, state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func saveTusState(oid string, state *tusState) error {
+	path, err := tusStatePath(oid)
+	if err != nil {
+		return err
+	}
+
+	by, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, by, 0644)
+}
+
+func discardTusState(oid string) {
+	if path, err := tusStatePath(oid); err == nil {
+		os.Remove(path)
+	}
+}
+
+// tusChunkSize returns the configured PATCH body size used for resumable
+// uploads, falling back to defaultTusChunkSize.
+func tusChunkSize() int64 {
+	return int64(config.Config.FetchInt("lfs.tuschunksize", defaultTusChunkSize))
+}
+
+// tusUnavailableError indicates the tus upload can't be resumed (the server
+// expired it, or the handshake never produced a usable Location) and the
+// caller should fall back to a classic single-shot upload instead of
+// retrying the tus flow. It deliberately does not satisfy errutil's
+// retriable classification: retrying the same tus upload would just repeat
+// the failure.
+type tusUnavailableError struct {
+	err error
+}
+
+func (e *tusUnavailableError) Error() string { return e.err.Error() }
+func (e *tusUnavailableError) Unwrap() error { return e.err }
+
+// transferTus performs a resumable chunked upload of u.object using the
+// tus.io protocol, resuming from a previously persisted upload offset when
+// one exists. A *tusUnavailableError return means the caller should fall
+// back to the classic single-shot PUT; any other error is retriable or
+// terminal per errutil and should propagate so withRetry can resume this
+// same tus upload on its next attempt.
+func (u *Uploadable) transferTus(cb progress.CopyCallback) error {
+	location, err := u.tusCreateOrResumeUpload()
+	if err != nil {
+		return err
+	}
+
+	offset, err := u.tusCurrentOffset(location)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(u.OidPath)
+	if err != nil {
+		return errutil.Error(err)
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return errutil.Error(err)
+	}
+
+	for offset < u.object.Size {
+		sent := offset
+
+		// A fresh CallbackReader per chunk means its own "bytes read"
+		// count starts at zero each time; adding the bytes already
+		// sent in prior chunks gives the absolute position without
+		// double-counting them.
+		wcb := func(total, n int64, current int) error {
+			return cb(total, sent+n, current)
+		}
+		reader := &progress.CallbackReader{
+			C:         wcb,
+			TotalSize: u.object.Size,
+			Reader:    file,
+		}
+
+		n, err := u.tusPatch(location, reader, offset)
+		if err != nil {
+			return err
+		}
+		offset += n
+	}
+
+	discardTusState(u.object.Oid)
+	return nil
+}
+
+func (u *Uploadable) tusCreateOrResumeUpload() (string, error) {
+	if state, err := loadTusState(u.object.Oid); err == nil && len(state.Location) > 0 {
+		return state.Location, nil
+	}
+
+	rel, ok := u.object.Rel("upload")
+	if !ok {
+		return "", errutil.Errorf(nil, "Object %s has no upload action", u.object.Oid)
+	}
+
+	req, err := http.NewRequest("POST", rel.Href, nil)
+	if err != nil {
+		return "", errutil.Error(err)
+	}
+
+	req.Header.Set("Tus-Resumable", "1.0.0")
+	req.Header.Set("Upload-Length", strconv.FormatInt(u.object.Size, 10))
+	req.Header.Set("Upload-Metadata", fmt.Sprintf("oid %s,filename %s",
+		base64.StdEncoding.EncodeToString([]byte(u.object.Oid)),
+		base64.StdEncoding.EncodeToString([]byte(u.Filename)),
+	))
+	for key, value := range rel.Header {
+		req.Header.Set(key, value)
+	}
+
+	res, err := api.DoHTTP(config.Config, req)
+	if err != nil {
+		return "", errutil.NewRetriableError(err)
+	}
+	defer res.Body.Close()
+
+	if err := tusStatusErr(res.StatusCode, fmt.Sprintf("tus create failed for %s", u.object.Oid)); err != nil {
+		return "", err
+	}
+
+	location := res.Header.Get("Location")
+	if len(location) == 0 {
+		return "", &tusUnavailableError{fmt.Errorf("server did not return a tus upload Location for %s", u.object.Oid)}
+	}
+
+	if err := saveTusState(u.object.Oid, &tusState{Location: location}); err != nil {
+		return "", errutil.Error(err)
+	}
+
+	return location, nil
+}
+
+func (u *Uploadable) tusCurrentOffset(location string) (int64, error) {
+	req, err := http.NewRequest("HEAD", location, nil)
+	if err != nil {
+		return 0, errutil.Error(err)
+	}
+	req.Header.Set("Tus-Resumable", "1.0.0")
+
+	res, err := api.DoHTTP(config.Config, req)
+	if err != nil {
+		return 0, errutil.NewRetriableError(err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == 404 || res.StatusCode == 410 {
+		discardTusState(u.object.Oid)
+		return 0, &tusUnavailableError{fmt.Errorf("tus upload for %s expired", u.object.Oid)}
+	}
+	if err := tusStatusErr(res.StatusCode, fmt.Sprintf("tus HEAD failed for %s", u.object.Oid)); err != nil {
+		return 0, err
+	}
+
+	offset, err := strconv.ParseInt(res.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil {
+		return 0, errutil.Error(err)
+	}
+	return offset, nil
+}
+
+// tusPatch sends up to one chunk (lfs.tuschunksize bytes) of reader starting
+// at offset, returning the number of bytes the server accepted.
+func (u *Uploadable) tusPatch(location string, reader io.Reader, offset int64) (int64, error) {
+	chunk := io.LimitReader(reader, tusChunkSize())
+
+	req, err := http.NewRequest("PATCH", location, chunk)
+	if err != nil {
+		return 0, errutil.Error(err)
+	}
+	req.Header.Set("Tus-Resumable", "1.0.0")
+	req.Header.Set("Upload-Offset", strconv.FormatInt(offset, 10))
+	req.Header.Set("Content-Type", "application/offset+octet-stream")
+
+	res, err := api.DoHTTP(config.Config, req)
+	if err != nil {
+		return 0, errutil.NewRetriableError(err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == 409 || res.StatusCode == 410 {
+		discardTusState(u.object.Oid)
+		return 0, &tusUnavailableError{fmt.Errorf("tus upload for %s expired, falling back", u.object.Oid)}
+	}
+	if err := tusStatusErr(res.StatusCode, fmt.Sprintf("tus PATCH failed for %s", u.object.Oid)); err != nil {
+		return 0, err
+	}
+
+	newOffset, err := strconv.ParseInt(res.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil {
+		return 0, errutil.Error(err)
+	}
+
+	return newOffset - offset, nil
+}
+
+// tusStatusErr classifies a non-2xx tus response per the shared retry
+// policy (5xx/408/429 are retriable) so withRetry's backoff and circuit
+// breaker apply to tus the same way they do to every other transfer path.
+// 2xx statuses return nil.
+func tusStatusErr(status int, msg string) error {
+	if status >= 200 && status < 300 {
+		return nil
+	}
+	err := fmt.Errorf("%s: %d", msg, status)
+	if isRetriableStatus(status) {
+		return errutil.NewRetriableError(err)
+	}
+	return errutil.Error(err)
+}
@@ -0,0 +1,43 @@
+package lfs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/github/git-lfs/errutil"
+)
+
+func TestTusStatusErrClassification(t *testing.T) {
+	if err := tusStatusErr(200, "ok"); err != nil {
+		t.Fatalf("2xx should not error, got %v", err)
+	}
+
+	for _, status := range []int{500, 502, 503, 408, 429} {
+		if err := tusStatusErr(status, "x"); err == nil {
+			t.Errorf("status %d: expected an error", status)
+		} else if !errutil.IsRetriable(err) {
+			t.Errorf("status %d: expected a retriable error", status)
+		}
+	}
+
+	for _, status := range []int{400, 401, 403, 404} {
+		if err := tusStatusErr(status, "x"); err == nil {
+			t.Errorf("status %d: expected an error", status)
+		} else if errutil.IsRetriable(err) {
+			t.Errorf("status %d: expected a terminal error", status)
+		}
+	}
+}
+
+func TestTusUnavailableErrorUnwraps(t *testing.T) {
+	inner := errors.New("expired")
+	err := &tusUnavailableError{inner}
+
+	var unavailable *tusUnavailableError
+	if !errors.As(err, &unavailable) {
+		t.Fatal("expected errors.As to match *tusUnavailableError")
+	}
+	if !errors.Is(err, inner) {
+		t.Fatal("expected errors.Is to reach the wrapped error")
+	}
+}
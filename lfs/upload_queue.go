@@ -1,9 +1,11 @@
 package lfs
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/github/git-lfs/api"
 	"github.com/github/git-lfs/config"
@@ -13,11 +15,15 @@ import (
 
 // Uploadable describes a file that can be uploaded.
 type Uploadable struct {
-	oid      string
-	OidPath  string
-	Filename string
-	size     int64
-	object   *api.ObjectResource
+	oid            string
+	OidPath        string
+	checkPath      string
+	Filename       string
+	size           int64
+	uploadSize     int64
+	object         *api.ObjectResource
+	encFingerprint string
+	encCipherOid   string
 }
 
 // NewUploadable builds the Uploadable from the given information.
@@ -39,25 +45,74 @@ func NewUploadable(oid, filename string) (*Uploadable, error) {
 		return nil, errutil.Errorf(err, "Error uploading file %s (%s)", filename, oid)
 	}
 
-	return &Uploadable{oid: oid, OidPath: localMediaPath, Filename: filename, size: fi.Size()}, nil
+	u := &Uploadable{
+		oid:        oid,
+		OidPath:    localMediaPath,
+		checkPath:  localMediaPath,
+		Filename:   filename,
+		size:       fi.Size(),
+		uploadSize: fi.Size(),
+	}
+
+	if encryptionEnabled() {
+		enc, err := encryptForUpload(oid, localMediaPath)
+		if err != nil {
+			return nil, errutil.Errorf(err, "Error encrypting file %s (%s)", filename, oid)
+		}
+		// oid/size must stay the plaintext identity the git pointer records
+		// and that Check()/the batch request operate on — only the bytes
+		// actually placed on the wire (OidPath, uploadSize) change. The
+		// ciphertext's own digest still travels as a side-channel via
+		// SetObject's X-LFS-Encryption header, for servers that want to
+		// verify what they received without it being the object's identity.
+		u.OidPath = enc.Path
+		u.uploadSize = enc.Size
+		u.encFingerprint = enc.Fingerprint
+		u.encCipherOid = enc.Oid
+	}
+
+	return u, nil
 }
 
 func (u *Uploadable) Check() (*api.ObjectResource, error) {
-	return api.UploadCheck(u.OidPath)
+	return api.UploadCheck(u.checkPath)
 }
 
+// Transfer uploads u.object, retrying transient failures with backoff and
+// tripping a per-endpoint circuit breaker after repeated failures.
 func (u *Uploadable) Transfer(cb progress.CopyCallback) error {
+	endpoint := u.object.Oid
+	if rel, ok := u.object.Rel("upload"); ok {
+		endpoint = rel.Href
+	}
+
+	if len(u.encFingerprint) > 0 {
+		// u.OidPath is a temp ciphertext under .git/lfs/enc/; remove it once
+		// every retry attempt is done (success or final failure), not inside
+		// transferOnce where a mid-loop removal would break the next retry.
+		defer os.Remove(u.OidPath)
+	}
+
+	return withRetry(endpoint, cb, u.transferOnce)
+}
+
+var warnedChunkingDisabledForEncryption sync.Once
+
+// warnChunkingDisabledForEncryption tells the user, once per process, that
+// lfs.contentchunking is being skipped because encryption makes it useless.
+func warnChunkingDisabledForEncryption(oid string) {
+	warnedChunkingDisabledForEncryption.Do(func() {
+		fmt.Fprintf(os.Stderr, "warning: lfs.contentchunking is enabled but encryption makes its chunk boundaries unstable; skipping chunking for %s and any other encrypted object\n", oid)
+	})
+}
+
+func (u *Uploadable) transferOnce(cb progress.CopyCallback) error {
 	wcb := func(total, read int64, current int) error {
 		cb(total, read, current)
 		return nil
 	}
 
-	path, err := LocalMediaPath(u.object.Oid)
-	if err != nil {
-		return errutil.Error(err)
-	}
-
-	file, err := os.Open(path)
+	file, err := os.Open(u.OidPath)
 	if err != nil {
 		return errutil.Error(err)
 	}
@@ -69,6 +124,60 @@ func (u *Uploadable) Transfer(cb progress.CopyCallback) error {
 		Reader:    file,
 	}
 
+	if mp, ok := u.isMultipart(); ok {
+		return u.transferMultipart(mp, cb)
+	}
+
+	if contentChunkingEnabled() {
+		if len(u.encFingerprint) > 0 {
+			// u.OidPath is the AES-256-GCM ciphertext here, not the
+			// plaintext. A per-object key and counter-derived frame
+			// nonces mean identical plaintext in two different objects, or
+			// at two different frame positions, never produces the same
+			// ciphertext bytes, so content-defined dedup can never find a
+			// match - chunking would only add QueryChunks round trips and
+			// chunk uploads that can never be skipped. Warn once and skip
+			// straight to whichever of tus/classic this object uses.
+			warnChunkingDisabledForEncryption(u.oid)
+		} else {
+			err := u.transferChunked(cb)
+			if err == nil {
+				return nil
+			}
+
+			var unavailable *chunkingUnavailableError
+			if !errors.As(err, &unavailable) {
+				// A retriable or terminal error from the chunking
+				// attempt itself (network blip, rejected chunk, bad
+				// manifest, ...): propagate it so withRetry's backoff
+				// resumes this same chunked upload on the next attempt
+				// instead of silently falling through to tus/classic.
+				return err
+			}
+			// Server doesn't advertise chunking support; fall back to
+			// tus/classic below.
+		}
+	}
+
+	if u.tusResumable() {
+		err := u.transferTus(cb)
+		if err == nil {
+			return nil
+		}
+
+		var unavailable *tusUnavailableError
+		if !errors.As(err, &unavailable) {
+			// A retriable or terminal error from the tus flow itself
+			// (network blip, 5xx, ...): propagate it so withRetry's
+			// backoff resumes this same tus upload on the next
+			// attempt instead of silently restarting from scratch.
+			return err
+		}
+		// The tus upload can't be resumed (expired, or the handshake
+		// never produced a usable Location); fall back to the classic
+		// single-shot upload below.
+	}
+
 	return api.UploadObject(u.object, reader)
 }
 
@@ -90,6 +199,15 @@ func (u *Uploadable) Name() string {
 
 func (u *Uploadable) SetObject(o *api.ObjectResource) {
 	u.object = o
+
+	if len(u.encFingerprint) > 0 {
+		if rel, ok := u.object.Rel("upload"); ok {
+			if rel.Header == nil {
+				rel.Header = make(map[string]string)
+			}
+			rel.Header["X-LFS-Encryption"] = fmt.Sprintf("alg=%s;fp=%s;ctoid=%s", encryptionAlgorithm, u.encFingerprint, u.encCipherOid)
+		}
+	}
 }
 
 // NewUploadQueue builds an UploadQueue, allowing `workers` concurrent uploads.